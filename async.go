@@ -0,0 +1,161 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy controls what an AsyncWriter does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// BlockOnFull makes Write block until space frees up.
+	BlockOnFull OverflowPolicy = iota
+	// DropOldest discards the oldest queued entry to make room for the new one.
+	DropOldest
+	// DropNewest discards the entry being written, keeping the queue as-is.
+	DropNewest
+)
+
+// ErrAsyncWriterClosed is returned by Write after the AsyncWriter has been closed.
+var ErrAsyncWriterClosed = errors.New("log: async writer closed")
+
+// AsyncStats reports the running totals for an AsyncWriter.
+type AsyncStats struct {
+	Enqueued uint64
+	Dropped  uint64
+	Written  uint64
+}
+
+// AsyncWriter wraps an io.Writer with a bounded ring buffer drained by a
+// background goroutine, so that a slow sink (disk, network, a slow
+// terminal) does not stall the goroutine calling Write. Under BlockOnFull,
+// Write does wait while the queue is full.
+type AsyncWriter struct {
+	w      io.Writer
+	policy OverflowPolicy
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    [][]byte
+	head   int
+	size   int
+	closed bool
+	done   chan struct{}
+
+	enqueued atomic.Uint64
+	dropped  atomic.Uint64
+	written  atomic.Uint64
+}
+
+// NewAsyncWriter returns an AsyncWriter of capacity bufSize wrapping w.
+// Use Logger.SetOutput(log.NewAsyncWriter(os.Stderr, 4096, log.DropOldest))
+// to make an existing logger non-blocking without touching its callers.
+func NewAsyncWriter(w io.Writer, bufSize int, policy OverflowPolicy) *AsyncWriter {
+	if bufSize <= 0 {
+		bufSize = 1
+	}
+	aw := &AsyncWriter{
+		w:      w,
+		policy: policy,
+		buf:    make([][]byte, bufSize),
+		done:   make(chan struct{}),
+	}
+	aw.cond = sync.NewCond(&aw.mu)
+	go aw.drain()
+	return aw
+}
+
+// Write enqueues p for asynchronous delivery to the wrapped writer. p is
+// copied, so the caller may reuse it once Write returns.
+func (aw *AsyncWriter) Write(p []byte) (int, error) {
+	cp := append([]byte(nil), p...)
+
+	aw.mu.Lock()
+	for aw.size == len(aw.buf) && !aw.closed {
+		switch aw.policy {
+		case DropNewest:
+			aw.dropped.Add(1)
+			aw.mu.Unlock()
+			return len(p), nil
+		case DropOldest:
+			aw.buf[aw.head] = nil
+			aw.head = (aw.head + 1) % len(aw.buf)
+			aw.size--
+			aw.dropped.Add(1)
+		default: // BlockOnFull
+			aw.cond.Wait()
+		}
+	}
+	if aw.closed {
+		aw.mu.Unlock()
+		return 0, ErrAsyncWriterClosed
+	}
+	idx := (aw.head + aw.size) % len(aw.buf)
+	aw.buf[idx] = cp
+	aw.size++
+	aw.enqueued.Add(1)
+	aw.cond.Broadcast()
+	aw.mu.Unlock()
+	return len(p), nil
+}
+
+func (aw *AsyncWriter) drain() {
+	for {
+		aw.mu.Lock()
+		for aw.size == 0 && !aw.closed {
+			aw.cond.Wait()
+		}
+		if aw.size == 0 && aw.closed {
+			aw.mu.Unlock()
+			close(aw.done)
+			return
+		}
+		entry := aw.buf[aw.head]
+		aw.buf[aw.head] = nil
+		aw.head = (aw.head + 1) % len(aw.buf)
+		aw.size--
+		aw.cond.Broadcast()
+		aw.mu.Unlock()
+
+		if _, err := aw.w.Write(entry); err == nil {
+			aw.written.Add(1)
+		}
+	}
+}
+
+// Flush blocks until the queue is empty, i.e. until the background
+// goroutine has taken every queued entry off it. The last entry taken may
+// still be inside the wrapped writer's Write when Flush returns. After
+// Close, Flush returns at once.
+func (aw *AsyncWriter) Flush() {
+	aw.mu.Lock()
+	for aw.size > 0 && !aw.closed {
+		aw.cond.Wait()
+	}
+	aw.mu.Unlock()
+}
+
+// Close drains the queue and stops the background goroutine. Writes after
+// Close return ErrAsyncWriterClosed.
+func (aw *AsyncWriter) Close() error {
+	aw.mu.Lock()
+	if !aw.closed {
+		aw.closed = true
+		aw.cond.Broadcast()
+	}
+	aw.mu.Unlock()
+	<-aw.done
+	return nil
+}
+
+// Stats returns the AsyncWriter's running counts.
+func (aw *AsyncWriter) Stats() AsyncStats {
+	return AsyncStats{
+		Enqueued: aw.enqueued.Load(),
+		Dropped:  aw.dropped.Load(),
+		Written:  aw.written.Load(),
+	}
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestAsyncWriterBlockOnFull(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	aw := NewAsyncWriter(lockedWriter{&mu, &buf}, 4, BlockOnFull)
+	for i := 0; i < 20; i++ {
+		if _, err := aw.Write([]byte("x")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	aw.Close()
+
+	stats := aw.Stats()
+	if stats.Enqueued != 20 || stats.Written != 20 || stats.Dropped != 0 {
+		t.Fatalf("Stats() = %+v, want Enqueued=20 Written=20 Dropped=0", stats)
+	}
+	if buf.Len() != 20 {
+		t.Errorf("buf.Len() = %d, want 20", buf.Len())
+	}
+}
+
+func TestAsyncWriterDropOldest(t *testing.T) {
+	block := make(chan struct{})
+	w := &blockingWriter{unblock: block}
+	aw := NewAsyncWriter(w, 4, DropOldest)
+
+	// Prime the drain goroutine with a write that blocks until we release
+	// it, so the remaining writes below queue up instead of draining.
+	aw.Write([]byte("0"))
+	for i := 1; i < 20; i++ {
+		aw.Write([]byte("x"))
+	}
+	close(block)
+	aw.Close()
+
+	stats := aw.Stats()
+	if stats.Enqueued != 20 {
+		t.Errorf("Stats().Enqueued = %d, want 20", stats.Enqueued)
+	}
+	if stats.Dropped == 0 {
+		t.Errorf("Stats().Dropped = 0, want > 0 under DropOldest pressure")
+	}
+}
+
+func TestAsyncWriterDropNewest(t *testing.T) {
+	block := make(chan struct{})
+	w := &blockingWriter{unblock: block}
+	aw := NewAsyncWriter(w, 4, DropNewest)
+
+	aw.Write([]byte("0"))
+	for i := 1; i < 20; i++ {
+		aw.Write([]byte("x"))
+	}
+	close(block)
+	aw.Close()
+
+	stats := aw.Stats()
+	if stats.Dropped == 0 {
+		t.Errorf("Stats().Dropped = 0, want > 0 under DropNewest pressure")
+	}
+	if stats.Enqueued+stats.Dropped < 20 {
+		t.Errorf("Stats().Enqueued+Dropped = %d, want >= 20", stats.Enqueued+stats.Dropped)
+	}
+}
+
+func TestAsyncWriterConcurrentClose(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	aw := NewAsyncWriter(lockedWriter{&mu, &buf}, 4, BlockOnFull)
+	aw.Write([]byte("x"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			aw.Close()
+		}()
+	}
+	wg.Wait()
+
+	if _, err := aw.Write([]byte("y")); err != ErrAsyncWriterClosed {
+		t.Errorf("Write() after Close = %v, want ErrAsyncWriterClosed", err)
+	}
+}
+
+type lockedWriter struct {
+	mu  *sync.Mutex
+	buf *bytes.Buffer
+}
+
+func (w lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+// blockingWriter blocks its first Write until unblock is closed, giving a
+// test time to queue enough writes to trigger overflow handling before the
+// drain goroutine can keep up.
+type blockingWriter struct {
+	unblock chan struct{}
+	once    sync.Once
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { <-w.unblock })
+	return len(p), nil
+}
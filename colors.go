@@ -0,0 +1,14 @@
+package log
+
+// ANSI color escape codes used to highlight log output on a terminal.
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+	Blue   = "\033[34m"
+	Purple = "\033[35m"
+	Cyan   = "\033[36m"
+	White  = "\033[37m"
+	Gray   = "\033[90m"
+)
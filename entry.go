@@ -0,0 +1,101 @@
+package log
+
+import (
+	"os"
+	"runtime"
+	"time"
+)
+
+// Entry is the structured representation of a single log line. It is what
+// gets handed to a Formatter, and is also what WithFields builds up before
+// it is emitted.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	File    string
+	Line    int
+	Func    string
+	Err     error
+	Fields  map[string]any
+
+	logger *Logger
+}
+
+// Formatter turns an Entry into the bytes written to a Logger's output.
+type Formatter interface {
+	Format(e Entry) ([]byte, error)
+}
+
+// WithFields returns an *Entry carrying fields that will be attached to
+// whichever level method (Info, Error, ...) is called on it, e.g.:
+//
+//	log.WithFields(map[string]any{"user_id": id}).Info("login")
+func WithFields(fields map[string]any) *Entry { return std.WithFields(fields) }
+
+// WithFields returns an *Entry bound to l, carrying fields that will be
+// attached to whichever level method is called on it.
+func (l *Logger) WithFields(fields map[string]any) *Entry {
+	return &Entry{logger: l, Fields: fields}
+}
+
+// log calls outputEntry at calldepth 3: outputEntry, log, and the exported
+// Entry method (Info, Error, ...) each add one frame above the user's call
+// site.
+func (e *Entry) log(lv Level, msg string, err error) error {
+	return e.logger.outputEntry(lv, 3, msg, err, e.Fields)
+}
+
+func (e *Entry) Trace(msg string) error { return e.log(LevelTrace, msg, nil) }
+func (e *Entry) Debug(msg string) error { return e.log(LevelDebug, msg, nil) }
+func (e *Entry) Info(msg string) error  { return e.log(LevelInfo, msg, nil) }
+func (e *Entry) Warn(msg string) error  { return e.log(LevelWarn, msg, nil) }
+func (e *Entry) Error(msg string, err error) error {
+	return e.log(LevelError, msg, err)
+}
+
+// Fatal logs at LevelFatal and then calls os.Exit(1).
+func (e *Entry) Fatal(msg string) {
+	e.log(LevelFatal, msg, nil)
+	os.Exit(1)
+}
+
+func (e *Entry) Panic(msg string) {
+	e.log(LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// outputEntry builds and writes an Entry for the given level. calldepth is
+// the number of stack frames between runtime.Caller and the original
+// caller, matching the convention of the stdlib log package's Output.
+//
+// file/line/func are captured here whenever an installed Formatter might
+// render them; whether they're rendered is up to that Formatter (for
+// TextFormatter, the HeaderTemplate and Lshortfile/Llongfile, as before).
+// runtime.Caller is skipped entirely, leaving them empty, when nothing
+// installed would use its result (see needsCaller).
+func (l *Logger) outputEntry(lv Level, calldepth int, msg string, err error, fields map[string]any) error {
+	if lv < l.GetLevel() {
+		return nil
+	}
+	now := time.Now()
+	var file string
+	var line int
+	var fn string
+	if l.needsCaller() {
+		pc, f, ln, ok := runtime.Caller(calldepth)
+		if ok {
+			file, line = f, ln
+			if fv := runtime.FuncForPC(pc); fv != nil {
+				fn = fv.Name()
+			}
+		} else {
+			file = "???"
+		}
+	}
+	return l.write(Entry{
+		Time: now, Level: lv, Message: msg,
+		File: file, Line: line, Func: fn,
+		Err: err, Fields: fields,
+	})
+}
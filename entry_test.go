@@ -0,0 +1,65 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithFieldsInfoEndToEnd(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "", Lshortfile)
+	l.SetFormatter(&LogfmtFormatter{})
+
+	if err := l.WithFields(map[string]any{"user_id": 7}).Info("login"); err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "msg=login") {
+		t.Errorf("output = %q, want msg=login", got)
+	}
+	if !strings.Contains(got, "user_id=7") {
+		t.Errorf("output = %q, want user_id=7", got)
+	}
+	if !strings.Contains(got, "entry_test.go") {
+		t.Errorf("output = %q, want caller in entry_test.go, got a frame from inside the package instead", got)
+	}
+	if strings.Contains(got, "entry.go:") {
+		t.Errorf("output = %q, reported caller is inside entry.go itself: calldepth is wrong", got)
+	}
+}
+
+func TestWithFieldsErrorEndToEnd(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "", 0)
+	l.SetFormatter(&JSONFormatter{})
+
+	err := errors.New("boom")
+	if werr := l.WithFields(map[string]any{"attempt": 2}).Error("failed", err); werr != nil {
+		t.Fatalf("Error: %v", werr)
+	}
+
+	got := buf.String()
+	for _, want := range []string{`"msg":"failed"`, `"error":"boom"`, `"attempt":2`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output = %q, want substring %q", got, want)
+		}
+	}
+}
+
+func TestWithFieldsPackageLevel(t *testing.T) {
+	var buf bytes.Buffer
+	orig := std
+	std = New(&buf, "", Lshortfile)
+	std.SetFormatter(&LogfmtFormatter{})
+	defer func() { std = orig }()
+
+	if err := WithFields(map[string]any{"k": "v"}).Info("hi"); err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "k=v") || !strings.Contains(got, "entry_test.go") {
+		t.Errorf("output = %q, want fields and caller from this file", got)
+	}
+}
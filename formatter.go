@@ -0,0 +1,198 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// isTerminal reports whether w looks like an interactive terminal, so
+// TextFormatter can decide whether to emit color codes.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// TextFormatter renders the package's colored, human-readable output.
+// Color is auto-disabled when the destination isn't a TTY or when
+// NO_COLOR is set (https://no-color.org).
+type TextFormatter struct {
+	Flags    int    // same bits as Ldate/Ltime/Lshortfile/...
+	Prefix   string // prefix on each line; see Lmsgprefix
+	Color    bool
+	Template []templateInstr // compiled by (*Logger).SetTemplate; NewTextFormatter sets defaultTemplate
+}
+
+// NewTextFormatter returns a TextFormatter configured for out, auto-detecting
+// whether color should be enabled.
+func NewTextFormatter(out io.Writer, flag int, prefix string) *TextFormatter {
+	return &TextFormatter{
+		Flags:    flag,
+		Prefix:   prefix,
+		Color:    isTerminal(out) && os.Getenv("NO_COLOR") == "",
+		Template: defaultTemplate,
+	}
+}
+
+func (f *TextFormatter) Format(e Entry) ([]byte, error) {
+	var buf []byte
+	if f.Color {
+		buf = append(buf, e.Level.color()...)
+	}
+	if f.Flags&Lmsgprefix == 0 {
+		buf = append(buf, f.Prefix...)
+	}
+	buf = renderTemplate(buf, f.Template, e, f.Flags, f.Prefix)
+	for _, k := range sortedKeys(e.Fields) {
+		buf = append(buf, ' ')
+		buf = append(buf, k...)
+		buf = append(buf, '=')
+		buf = append(buf, fieldString(e.Fields[k])...)
+	}
+	if len(buf) == 0 || buf[len(buf)-1] != '\n' {
+		buf = append(buf, '\n')
+	}
+	if f.Color {
+		buf = append(buf, Reset...)
+	}
+	return buf, nil
+}
+
+// JSONFormatter renders one JSON object per line with a stable key order:
+// ts, level, msg, caller, func, error, followed by any merged fields.
+type JSONFormatter struct{}
+
+func (f *JSONFormatter) Format(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeJSONField(&buf, "ts", e.Time.Format(rfc3339Nano))
+	writeJSONField(&buf, "level", e.Level.String())
+	writeJSONField(&buf, "msg", e.Message)
+	if e.File != "" {
+		writeJSONField(&buf, "caller", e.File+":"+strconv.Itoa(e.Line))
+	}
+	if e.Func != "" {
+		writeJSONField(&buf, "func", e.Func)
+	}
+	if e.Err != nil {
+		writeJSONField(&buf, "error", e.Err.Error())
+	}
+	for _, k := range sortedKeys(e.Fields) {
+		buf.WriteByte(',')
+		kb, _ := json.Marshal(k)
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(e.Fields[k])
+		if err != nil {
+			vb, _ = json.Marshal(fieldString(e.Fields[k]))
+		}
+		buf.Write(vb)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+func writeJSONField(buf *bytes.Buffer, key, val string) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+	kb, _ := json.Marshal(key)
+	vb, _ := json.Marshal(val)
+	buf.Write(kb)
+	buf.WriteByte(':')
+	buf.Write(vb)
+}
+
+// LogfmtFormatter renders space-separated k=v pairs, quoting values that
+// contain whitespace or an '=' or '"'.
+type LogfmtFormatter struct{}
+
+func (f *LogfmtFormatter) Format(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	writeLogfmtField(&buf, "ts", e.Time.Format(rfc3339Nano))
+	writeLogfmtField(&buf, "level", e.Level.String())
+	writeLogfmtField(&buf, "msg", e.Message)
+	if e.File != "" {
+		writeLogfmtField(&buf, "caller", e.File+":"+strconv.Itoa(e.Line))
+	}
+	if e.Func != "" {
+		writeLogfmtField(&buf, "func", e.Func)
+	}
+	if e.Err != nil {
+		writeLogfmtField(&buf, "error", e.Err.Error())
+	}
+	for _, k := range sortedKeys(e.Fields) {
+		writeLogfmtField(&buf, k, fieldString(e.Fields[k]))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func writeLogfmtField(buf *bytes.Buffer, key, val string) {
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	if logfmtNeedsQuote(val) {
+		buf.WriteString(strconv.Quote(val))
+	} else {
+		buf.WriteString(val)
+	}
+}
+
+func logfmtNeedsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '"' || r == '=' {
+			return true
+		}
+	}
+	return false
+}
+
+// rfc3339Nano avoids importing time solely for the constant name.
+const rfc3339Nano = "2006-01-02T15:04:05.999999999Z07:00"
+
+func sortedKeys(m map[string]any) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fieldString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case error:
+		return s.Error()
+	default:
+		return jsonFallback(v)
+	}
+}
+
+func jsonFallback(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
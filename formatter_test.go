@@ -0,0 +1,123 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEntry() Entry {
+	return Entry{
+		Time:    time.Date(2024, 3, 2, 15, 4, 5, 0, time.UTC),
+		Level:   LevelInfo,
+		Message: "hello",
+		File:    "main.go",
+		Line:    42,
+		Func:    "main.run",
+	}
+}
+
+func TestTextFormatter(t *testing.T) {
+	f := &TextFormatter{Template: defaultTemplate, Flags: Ldate | Ltime | Lshortfile}
+	body, err := f.Format(testEntry())
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := string(body)
+	for _, want := range []string{"2024/03/02 15:04:05", "[INFO]", "main.go:42", "main.run", "hello"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Format() = %q, want substring %q", got, want)
+		}
+	}
+	if !strings.HasSuffix(got, "\n") {
+		t.Errorf("Format() = %q, want trailing newline", got)
+	}
+}
+
+func TestTextFormatterFields(t *testing.T) {
+	f := &TextFormatter{Template: defaultTemplate}
+	e := testEntry()
+	e.Fields = map[string]any{"user": "alice"}
+	body, _ := f.Format(e)
+	if got := string(body); !strings.Contains(got, "user=alice") {
+		t.Errorf("Format() = %q, want field user=alice", got)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	f := &JSONFormatter{}
+	e := testEntry()
+	e.Err = errors.New("boom")
+	e.Fields = map[string]any{"attempt": 3}
+	body, err := f.Format(e)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := string(body)
+	for _, want := range []string{
+		`"level":"INFO"`, `"msg":"hello"`, `"caller":"main.go:42"`,
+		`"func":"main.run"`, `"error":"boom"`, `"attempt":3`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Format() = %q, want substring %q", got, want)
+		}
+	}
+}
+
+func TestLogfmtFormatter(t *testing.T) {
+	f := &LogfmtFormatter{}
+	e := testEntry()
+	e.Fields = map[string]any{"path": "has space"}
+	body, err := f.Format(e)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := string(body)
+	for _, want := range []string{"level=INFO", "msg=hello", "caller=main.go:42", `path="has space"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Format() = %q, want substring %q", got, want)
+		}
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	instrs, err := parseTemplate("{level}: {msg}")
+	if err != nil {
+		t.Fatalf("parseTemplate: %v", err)
+	}
+	buf := renderTemplate(nil, instrs, testEntry(), 0, "")
+	if got, want := string(buf), "INFO: hello"; got != want {
+		t.Errorf("renderTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestParseTemplateUnknownField(t *testing.T) {
+	if _, err := parseTemplate("{nope}"); err == nil {
+		t.Fatal("parseTemplate() with unknown field: want error, got nil")
+	}
+}
+
+func TestParseTemplateUnterminated(t *testing.T) {
+	if _, err := parseTemplate("{level"); err == nil {
+		t.Fatal("parseTemplate() with unterminated field: want error, got nil")
+	}
+}
+
+func TestSetLevelFiltersOutput(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "", 0)
+	l.SetFormatter(&LogfmtFormatter{})
+	l.SetLevel(LevelWarn)
+
+	l.outputEntry(LevelInfo, 1, "dropped", nil, nil)
+	if buf.Len() != 0 {
+		t.Fatalf("entry below level was written: %q", buf.String())
+	}
+
+	l.outputEntry(LevelError, 1, "kept", nil, nil)
+	if !strings.Contains(buf.String(), "msg=kept") {
+		t.Fatalf("entry at/above level missing, got %q", buf.String())
+	}
+}
@@ -0,0 +1,59 @@
+package log
+
+// Level is the severity of a log entry. Lower values are less severe.
+type Level int32
+
+const (
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+	LevelPanic
+)
+
+// String returns the upper-case name of the level, as written into the
+// log header so downstream tools can grep for it.
+func (lv Level) String() string {
+	switch lv {
+	case LevelTrace:
+		return "TRACE"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	case LevelFatal:
+		return "FATAL"
+	case LevelPanic:
+		return "PANIC"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// color returns the ANSI color associated with the level.
+func (lv Level) color() string {
+	switch lv {
+	case LevelTrace:
+		return Gray
+	case LevelDebug:
+		return Cyan
+	case LevelInfo:
+		return Blue
+	case LevelWarn:
+		return Yellow
+	case LevelError:
+		return Red
+	case LevelFatal:
+		return Purple
+	case LevelPanic:
+		return Purple
+	default:
+		return White
+	}
+}
@@ -4,11 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"runtime"
-	"strconv"
 	"sync"
 	"sync/atomic"
-	"time"
 )
 
 const (
@@ -23,32 +20,168 @@ const (
 )
 
 type Logger struct {
-	mu        sync.Mutex // ensures atomic writes; protects the following fields
-	prefix    string     // prefix on each line to identify the logger (but see Lmsgprefix)
-	flag      int        // properties
-	out       io.Writer  // destination for output
-	buf       []byte     // for accumulating text to write
-	isDiscard int32      // atomic boolean: whether out == io.Discard
+	outMu sync.Mutex // protects out; held only around the Write call
+	out   io.Writer  // destination for output
+
+	flag      atomic.Int32           // properties
+	prefix    atomic.Pointer[string] // prefix on each line to identify the logger (but see Lmsgprefix)
+	isDiscard atomic.Bool            // whether out == io.Discard
+	level     atomic.Int32           // Level: entries below this severity are dropped
+	formatter atomic.Pointer[Formatter]
+	template  atomic.Pointer[string] // raw string last passed to SetTemplate
+	handler   atomic.Pointer[MultiHandler]
 }
 
 func New(out io.Writer, prefix string, flag int) *Logger {
-	l := &Logger{out: out, prefix: prefix, flag: flag}
-	if out == io.Discard {
-		l.isDiscard = 1
-	}
+	l := &Logger{out: out}
+	l.flag.Store(int32(flag))
+	l.prefix.Store(&prefix)
+	l.level.Store(int32(LevelTrace))
+	l.isDiscard.Store(out == io.Discard)
+	f := Formatter(NewTextFormatter(out, flag, prefix))
+	l.formatter.Store(&f)
 	return l
 }
 
+// SetFormatter sets the Formatter used to render entries written through
+// this logger, replacing the default TextFormatter.
+func (l *Logger) SetFormatter(f Formatter) {
+	l.formatter.Store(&f)
+}
+
+// Formatter returns the logger's current Formatter.
+func (l *Logger) Formatter() Formatter {
+	if p := l.formatter.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// needsCaller reports whether the logger's active formatter(s) render any
+// of {file}/{line}/{func}, so outputEntry can skip the relatively costly
+// runtime.Caller call when nothing would use its result.
+func (l *Logger) needsCaller() bool {
+	if h := l.handler.Load(); h != nil {
+		for _, s := range h.Sinks {
+			if formatterNeedsCaller(s.Formatter) {
+				return true
+			}
+		}
+		return false
+	}
+	return formatterNeedsCaller(l.Formatter())
+}
+
+// formatterNeedsCaller reports whether f renders caller info. A
+// *TextFormatter only does so if its Template references file/line/func
+// AND its Flags set Lshortfile or Llongfile (renderTemplate gates those
+// fields on the same bits); other formatters (JSON, Logfmt, user-supplied)
+// are assumed to want it, since they attach caller info unconditionally
+// whenever it's present.
+func formatterNeedsCaller(f Formatter) bool {
+	tf, ok := f.(*TextFormatter)
+	if !ok {
+		return true
+	}
+	if tf.Flags&(Lshortfile|Llongfile) == 0 {
+		return false
+	}
+	for _, ins := range tf.Template {
+		if ins.field == "file" || ins.field == "line" || ins.field == "func" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSinks routes every entry through a MultiHandler instead of the
+// logger's single out/Formatter pair, so Error can go to stderr, a file
+// and a remote collector while Info stays local. Pass no sinks to go back
+// to the single-output behavior.
+func (l *Logger) SetSinks(sinks ...Sink) {
+	if len(sinks) == 0 {
+		l.handler.Store(nil)
+		return
+	}
+	l.handler.Store(NewMultiHandler(sinks...))
+}
+
 // SetOutput sets the output destination for the logger.
 func (l *Logger) SetOutput(w io.Writer) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
 	l.out = w
-	isDiscard := int32(0)
-	if w == io.Discard {
-		isDiscard = 1
+	l.isDiscard.Store(w == io.Discard)
+	if tf, ok := l.Formatter().(*TextFormatter); ok {
+		next := *tf
+		next.Color = isTerminal(w) && os.Getenv("NO_COLOR") == ""
+		l.SetFormatter(&next)
 	}
-	atomic.StoreInt32(&l.isDiscard, isDiscard)
+}
+
+// currentOut returns the logger's output destination, synchronized with
+// SetOutput so callers that need to read l.out (e.g. to build a fallback
+// TextFormatter) don't race with a concurrent SetOutput.
+func (l *Logger) currentOut() io.Writer {
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
+	return l.out
+}
+
+// SetFlags sets the output flags for the logger.
+func (l *Logger) SetFlags(flag int) {
+	l.flag.Store(int32(flag))
+	l.syncTextFormatter()
+}
+
+// Flags returns the output flags for the logger.
+func (l *Logger) Flags() int {
+	return int(l.flag.Load())
+}
+
+// SetPrefix sets the output prefix for the logger.
+//
+// A SetFlags/SetPrefix call racing with an in-flight Output may be
+// observed using the old flags/prefix for that one entry; callers that
+// need a hard cutover should quiesce logging first.
+func (l *Logger) SetPrefix(prefix string) {
+	l.prefix.Store(&prefix)
+	l.syncTextFormatter()
+}
+
+// syncTextFormatter refreshes the installed TextFormatter's Prefix/Flags
+// after SetPrefix/SetFlags, since Format reads them off the formatter
+// rather than the logger. Formatters other than *TextFormatter (JSON,
+// Logfmt, user-supplied) don't use Prefix/Flags and are left alone.
+func (l *Logger) syncTextFormatter() {
+	tf, ok := l.Formatter().(*TextFormatter)
+	if !ok {
+		return
+	}
+	next := *tf
+	next.Prefix = l.Prefix()
+	next.Flags = l.Flags()
+	l.SetFormatter(&next)
+}
+
+// Prefix returns the output prefix for the logger.
+func (l *Logger) Prefix() string {
+	if p := l.prefix.Load(); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// SetLevel sets the minimum severity the logger will emit. Entries below
+// this level are silently dropped, which is how callers can disable
+// Trace/Debug output in production without forking the package.
+func (l *Logger) SetLevel(lv Level) {
+	l.level.Store(int32(lv))
+}
+
+// GetLevel returns the logger's current minimum severity.
+func (l *Logger) GetLevel() Level {
+	return Level(l.level.Load())
 }
 
 var std = New(os.Stderr, "", LstdFlags)
@@ -56,6 +189,12 @@ var std = New(os.Stderr, "", LstdFlags)
 // Default returns the standard logger used by the package-level output functions.
 func Default() *Logger { return std }
 
+// SetLevel sets the minimum severity emitted by the standard logger.
+func SetLevel(lv Level) { std.SetLevel(lv) }
+
+// GetLevel returns the standard logger's current minimum severity.
+func GetLevel() Level { return std.GetLevel() }
+
 // Cheap integer to fixed-width decimal ASCII. Give a negative width to avoid zero-padding.
 func itoa(buf *[]byte, i int, wid int) {
 	// Assemble decimal in reverse order.
@@ -73,170 +212,117 @@ func itoa(buf *[]byte, i int, wid int) {
 	*buf = append(*buf, b[bp:]...)
 }
 
-// formatHeader writes log header to buf in following order:
-//   * l.prefix (if it's not blank and Lmsgprefix is unset),
-//   * date and/or time (if corresponding flags are provided),
-//   * file and line number (if corresponding flags are provided),
-//   * l.prefix (if it's not blank and Lmsgprefix is set).
-func (l *Logger) formatHeader(buf *[]byte, t time.Time, file string, line int) {
-	if l.flag&Lmsgprefix == 0 {
-		*buf = append(*buf, l.prefix...)
-	}
-	if l.flag&(Ldate|Ltime|Lmicroseconds) != 0 {
-		if l.flag&LUTC != 0 {
-			t = t.UTC()
-		}
-		if l.flag&Ldate != 0 {
-			year, month, day := t.Date()
-			itoa(buf, year, 4)
-			*buf = append(*buf, '/')
-			itoa(buf, int(month), 2)
-			*buf = append(*buf, '/')
-			itoa(buf, day, 2)
-			*buf = append(*buf, ' ')
-		}
-		if l.flag&(Ltime|Lmicroseconds) != 0 {
-			hour, min, sec := t.Clock()
-			itoa(buf, hour, 2)
-			*buf = append(*buf, ':')
-			itoa(buf, min, 2)
-			*buf = append(*buf, ':')
-			itoa(buf, sec, 2)
-			if l.flag&Lmicroseconds != 0 {
-				*buf = append(*buf, '.')
-				itoa(buf, t.Nanosecond()/1e3, 6)
-			}
-			*buf = append(*buf, ' ')
-		}
-	}
-	if l.flag&(Lshortfile|Llongfile) != 0 {
-		if l.flag&Lshortfile != 0 {
-			short := file
-			for i := len(file) - 1; i > 0; i-- {
-				if file[i] == '/' {
-					short = file[i+1:]
-					break
-				}
-			}
-			file = short
-		}
-		*buf = append(*buf, file...)
-		*buf = append(*buf, ':')
-		itoa(buf, line, -1)
-		*buf = append(*buf, ": "...)
-	}
-	if l.flag&Lmsgprefix != 0 {
-		*buf = append(*buf, l.prefix...)
-	}
-}
-
-func (l *Logger) Output(functionName, color string, err error, calldepth int, s string) error {
-	now := time.Now() // get this early.
-	var file string
-	var line int
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	if l.flag&(Lshortfile|Llongfile) != 0 {
-		// Release lock while getting caller info - it's expensive.
-		l.mu.Unlock()
-		var ok bool
-		_, file, line, ok = runtime.Caller(calldepth)
-		if !ok {
-			file = "???"
-			line = 0
-		}
-		l.mu.Lock()
-	}
-	l.buf = l.buf[:0]
-	l.formatHeader(&l.buf, now, file, line)
-	l.buf = append(l.buf, s...)
-	if len(s) == 0 || s[len(s)-1] != '\n' {
-		l.buf = append(l.buf, '\n')
-	}
+// Output writes a log entry of the given level and calldepth to the
+// logger's destination via its Formatter. It returns nil without writing
+// if lv is below the logger's configured level.
+func (l *Logger) Output(lv Level, calldepth int, s string) error {
+	return l.outputEntry(lv, calldepth+1, s, nil, nil)
+}
 
-	if functionName == "github.com/perfectogo/log.Error" {
-		if err != nil {
-			l.buf = []byte(color + "TIME: " + string(l.buf) + Reset)
-		} else {
-			l.buf = []byte(color + "TIME: " + string(l.buf) + Reset)
-		}
-	} else {
-		l.buf = []byte(color + "TIME: " + string(l.buf) + Reset)
+// write delivers e to the logger's MultiHandler if one was installed via
+// SetSinks, or otherwise renders it with the logger's Formatter and writes
+// the result to out. outMu is only held around the single-sink Write
+// call, so concurrent callers no longer serialize on formatting.
+func (l *Logger) write(e Entry) error {
+	if h := l.handler.Load(); h != nil {
+		return h.Write(e)
+	}
+	body, err := l.Formatter().Format(e)
+	if err != nil {
+		return err
 	}
-	_, err = l.out.Write(l.buf)
+	l.outMu.Lock()
+	_, err = l.out.Write(body)
+	l.outMu.Unlock()
 	return err
-
-}
-
-func getCurrentFuncName() string {
-	pc, _, _, _ := runtime.Caller(1)
-	return fmt.Sprintf("%s", runtime.FuncForPC(pc).Name())
 }
 
+// Println logs at LevelInfo. File, line and function are filled in from
+// the call site and rendered by the logger's HeaderTemplate rather than
+// being concatenated into the message.
 func Println(v ...any) {
-	pc, filename, line, _ := runtime.Caller(1)
-
-	fn := func(uintptr) string {
-		pc, _, _, _ := runtime.Caller(1)
-		return fmt.Sprintf("%s", runtime.FuncForPC(pc).Name())
+	if std.isDiscard.Load() {
+		return
 	}
+	std.outputEntry(LevelInfo, 2, fmt.Sprint(v...), nil, nil)
+}
 
-	fnName := getCurrentFuncName()
-	color := Green
-	if atomic.LoadInt32(&std.isDiscard) != 0 {
+func Trace(v ...any) {
+	if std.isDiscard.Load() {
 		return
 	}
+	std.outputEntry(LevelTrace, 2, fmt.Sprint(v...), nil, nil)
+}
 
-	color = White
-	std.Output(
-		fnName, color, nil, 2,
-		"\nPATH:\t"+filename+"\nFUNCTION: "+fn(pc)+"\nLOG LINE: "+strconv.Itoa(line)+color+"\nINFO: "+fmt.Sprint(v...)+Reset,
-	)
+func Debug(v ...any) {
+	if std.isDiscard.Load() {
+		return
+	}
+	std.outputEntry(LevelDebug, 2, fmt.Sprint(v...), nil, nil)
 }
 
 func Info(v ...any) {
-	fnName := getCurrentFuncName()
-	color := Blue
-	_, filename, line, _ := runtime.Caller(1)
-
-	if atomic.LoadInt32(&std.isDiscard) != 0 {
+	if std.isDiscard.Load() {
 		return
 	}
-	std.Output(
-		fnName, color, nil, 2,
-		"\n\tPATH: "+filename+"\n\tLOG LINE: "+strconv.Itoa(line)+"\n\tINFO: "+color+fmt.Sprint(v...)+Reset,
-	)
+	std.outputEntry(LevelInfo, 2, fmt.Sprint(v...), nil, nil)
 }
 
 func Error(msg string, err error) {
-	_, filename, line, _ := runtime.Caller(1)
-	fnName := getCurrentFuncName()
-	color := Green
-	if atomic.LoadInt32(&std.isDiscard) != 0 {
+	if std.isDiscard.Load() {
 		return
 	}
-	if err != nil {
-		color = Red
-		std.Output(
-			fnName, color, err, 2,
-			"\n\tPATH: "+filename+"\n\tLOG LINE: "+strconv.Itoa(line)+color+"\n\tMESSAGE: "+fmt.Sprint(msg)+"\n\tERROR: "+err.Error()+Reset,
-		)
-		return
-	}
-	std.Output(
-		fnName, color, err, 2,
-		"\n\tPATH: "+filename+"\n\tLOG LINE: "+strconv.Itoa(line)+color+"\n\tMESSAGE: "+fmt.Sprint(msg)+"\n\tERROR: NO ERROR"+Reset,
-	)
+	std.outputEntry(LevelError, 2, msg, err, nil)
 }
 
 func Warning(v ...any) {
-	_, filename, line, _ := runtime.Caller(1)
-	fnName := getCurrentFuncName()
-	color := Yellow
-	if atomic.LoadInt32(&std.isDiscard) != 0 {
+	if std.isDiscard.Load() {
 		return
 	}
-	std.Output(
-		fnName, color, nil, 2,
-		"\nWARNING LOG\n\tPATH: "+filename+"\n\tLOG LINE: "+strconv.Itoa(line)+"\n\tWARNING: "+color+fmt.Sprint(v...)+Reset)
+	std.outputEntry(LevelWarn, 2, fmt.Sprint(v...), nil, nil)
+}
+
+// Fatal logs the fmt.Sprint-formatted arguments at LevelFatal and then
+// calls os.Exit(1).
+func Fatal(v ...any) {
+	std.outputEntry(LevelFatal, 2, fmt.Sprint(v...), nil, nil)
+	os.Exit(1)
+}
+
+// Fatalln logs the fmt.Sprintln-formatted arguments at LevelFatal and then
+// calls os.Exit(1).
+func Fatalln(v ...any) {
+	std.outputEntry(LevelFatal, 2, fmt.Sprintln(v...), nil, nil)
+	os.Exit(1)
+}
+
+// Fatalf logs the fmt.Sprintf-formatted message at LevelFatal and then
+// calls os.Exit(1).
+func Fatalf(format string, v ...any) {
+	std.outputEntry(LevelFatal, 2, fmt.Sprintf(format, v...), nil, nil)
+	os.Exit(1)
+}
+
+// Panic logs the fmt.Sprint-formatted arguments at LevelPanic and then
+// panics with that message.
+func Panic(v ...any) {
+	s := fmt.Sprint(v...)
+	std.outputEntry(LevelPanic, 2, s, nil, nil)
+	panic(s)
+}
+
+// Panicln logs the fmt.Sprintln-formatted arguments at LevelPanic and then
+// panics with that message.
+func Panicln(v ...any) {
+	s := fmt.Sprintln(v...)
+	std.outputEntry(LevelPanic, 2, s, nil, nil)
+	panic(s)
+}
+
+// Panicf logs the fmt.Sprintf-formatted message at LevelPanic and then
+// panics with that message.
+func Panicf(format string, v ...any) {
+	s := fmt.Sprintf(format, v...)
+	std.outputEntry(LevelPanic, 2, s, nil, nil)
+	panic(s)
 }
@@ -0,0 +1,87 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetPrefixRefreshesFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "ORIG: ", 0)
+	l.SetPrefix("NEW: ")
+	l.Output(LevelInfo, 1, "hi")
+	if got := buf.String(); !strings.Contains(got, "NEW: ") || strings.Contains(got, "ORIG: ") {
+		t.Errorf("Output() = %q, want prefix NEW:, not ORIG:", got)
+	}
+}
+
+func TestSetFlagsRefreshesFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "p: ", Lmsgprefix)
+	l.Output(LevelInfo, 1, "hi")
+	before := buf.String()
+	if strings.HasPrefix(before, "p: ") {
+		t.Fatalf("Output() = %q, want prefix moved after header under Lmsgprefix", before)
+	}
+
+	buf.Reset()
+	l.SetFlags(0)
+	l.Output(LevelInfo, 1, "hi")
+	// Clearing Lmsgprefix should move the prefix back to the front of the
+	// line; if SetFlags didn't refresh the installed TextFormatter, the
+	// line would still have the prefix in the middle.
+	if after := buf.String(); !strings.HasPrefix(after, "p: ") {
+		t.Errorf("Output() after SetFlags(0) = %q, want prefix at line start", after)
+	}
+}
+
+func TestOutputRespectsFileLineFlags(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "", Ltime)
+	l.Output(LevelInfo, 1, "hi")
+	if got := buf.String(); strings.Contains(got, "log_test.go") || strings.Contains(got, "TestOutputRespectsFileLineFlags") {
+		t.Errorf("Output() = %q, want no file/line/func without Lshortfile/Llongfile", got)
+	}
+}
+
+func TestOutputRespectsDateTimeFlags(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "", Lshortfile)
+	l.Output(LevelInfo, 1, "hi")
+	got := buf.String()
+	if !strings.Contains(got, "log_test.go") {
+		t.Errorf("Output() = %q, want file/line with Lshortfile set", got)
+	}
+	// No Ldate/Ltime/Lmicroseconds: the line shouldn't start with a date.
+	if strings.HasPrefix(got, "20") {
+		t.Errorf("Output() = %q, want no leading date without Ldate/Ltime", got)
+	}
+}
+
+func TestOutputLmsgprefixBeforeMessage(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, "PFX: ", Lmsgprefix)
+	l.Output(LevelInfo, 1, "hello")
+	got := buf.String()
+	if !strings.Contains(got, "PFX: hello") {
+		t.Errorf("Output() = %q, want %q immediately before the message", got, "PFX: hello")
+	}
+	if strings.Contains(got, "helloPFX") {
+		t.Errorf("Output() = %q, prefix got glued onto the end of the message", got)
+	}
+}
+
+// BenchmarkConcurrent drives Output from many goroutines at once to show
+// that formatting no longer serializes on a single mutex: only the final
+// io.Discard write is now exclusive.
+func BenchmarkConcurrent(b *testing.B) {
+	l := New(io.Discard, "prefix: ", LstdFlags|Lshortfile)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Output(LevelInfo, 2, "benchmark message")
+		}
+	})
+}
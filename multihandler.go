@@ -0,0 +1,54 @@
+package log
+
+import "io"
+
+// Sink is one destination in a MultiHandler: entries below MinLevel are
+// skipped, and the rest are rendered with Formatter before being written
+// to Writer.
+type Sink struct {
+	Writer    io.Writer
+	MinLevel  Level
+	Formatter Formatter
+}
+
+// MultiHandler fans a single Entry out to several Sinks, formatting it
+// once per distinct Formatter rather than once per sink. This is how a
+// service routes Error to stderr, a file and a remote collector while
+// keeping Info local: one Sink per destination, with its own MinLevel
+// and Formatter.
+type MultiHandler struct {
+	Sinks []Sink
+}
+
+// NewMultiHandler returns a MultiHandler fanning out to sinks.
+func NewMultiHandler(sinks ...Sink) *MultiHandler {
+	return &MultiHandler{Sinks: sinks}
+}
+
+// Write renders e and delivers it to every Sink whose MinLevel it meets.
+// It returns the first error encountered, after attempting every sink.
+func (h *MultiHandler) Write(e Entry) error {
+	cache := make(map[Formatter][]byte, len(h.Sinks))
+	var firstErr error
+	for _, s := range h.Sinks {
+		if s.Formatter == nil || s.Writer == nil || e.Level < s.MinLevel {
+			continue
+		}
+		body, ok := cache[s.Formatter]
+		if !ok {
+			b, err := s.Formatter.Format(e)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			cache[s.Formatter] = b
+			body = b
+		}
+		if _, err := s.Writer.Write(body); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+// countingFormatter counts how many times Format is called, so tests can
+// confirm MultiHandler caches by Formatter instead of formatting per sink.
+type countingFormatter struct {
+	calls int
+}
+
+func (f *countingFormatter) Format(e Entry) ([]byte, error) {
+	f.calls++
+	return []byte(e.Message + "\n"), nil
+}
+
+func TestMultiHandlerPerSinkMinLevel(t *testing.T) {
+	var info, errs bytes.Buffer
+	h := NewMultiHandler(
+		Sink{Writer: &info, MinLevel: LevelInfo, Formatter: &LogfmtFormatter{}},
+		Sink{Writer: &errs, MinLevel: LevelError, Formatter: &LogfmtFormatter{}},
+	)
+
+	if err := h.Write(Entry{Level: LevelInfo, Message: "hi"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if info.Len() == 0 {
+		t.Error("info sink got nothing for a LevelInfo entry")
+	}
+	if errs.Len() != 0 {
+		t.Errorf("error sink got %q for a LevelInfo entry, want nothing", errs.String())
+	}
+
+	info.Reset()
+	errs.Reset()
+	if err := h.Write(Entry{Level: LevelError, Message: "boom"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if info.Len() == 0 || errs.Len() == 0 {
+		t.Errorf("both sinks should get a LevelError entry, got info=%q errs=%q", info.String(), errs.String())
+	}
+}
+
+func TestMultiHandlerCachesByFormatter(t *testing.T) {
+	var a, b bytes.Buffer
+	shared := &countingFormatter{}
+	h := NewMultiHandler(
+		Sink{Writer: &a, MinLevel: LevelTrace, Formatter: shared},
+		Sink{Writer: &b, MinLevel: LevelTrace, Formatter: shared},
+	)
+
+	if err := h.Write(Entry{Level: LevelInfo, Message: "hi"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if shared.calls != 1 {
+		t.Errorf("Formatter.Format called %d times, want 1 (cached across sinks)", shared.calls)
+	}
+	if a.String() != "hi\n" || b.String() != "hi\n" {
+		t.Errorf("sinks = %q, %q, want both %q", a.String(), b.String(), "hi\n")
+	}
+}
+
+func TestMultiHandlerSkipsIncompleteSinks(t *testing.T) {
+	h := NewMultiHandler(Sink{MinLevel: LevelTrace})
+	if err := h.Write(Entry{Level: LevelInfo, Message: "hi"}); err != nil {
+		t.Fatalf("Write with no Writer/Formatter: %v", err)
+	}
+}
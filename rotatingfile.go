@@ -0,0 +1,111 @@
+package log
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// RotatingFile is an io.Writer over a file that rotates to a timestamped
+// backup once it exceeds MaxSize bytes or MaxAge in wall-clock time,
+// in the spirit of lumberjack but without the external dependency.
+// A zero MaxSize or MaxAge disables that trigger.
+type RotatingFile struct {
+	Filename string
+	MaxSize  int64
+	MaxAge   time.Duration
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewRotatingFile returns a RotatingFile ready to write to filename.
+func NewRotatingFile(filename string, maxSize int64, maxAge time.Duration) *RotatingFile {
+	return &RotatingFile{Filename: filename, MaxSize: maxSize, MaxAge: maxAge}
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.openExisting(); err != nil {
+			return 0, err
+		}
+	} else if r.shouldRotate(len(p)) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) shouldRotate(n int) bool {
+	if r.MaxSize > 0 && r.size+int64(n) > r.MaxSize {
+		return true
+	}
+	if r.MaxAge > 0 && time.Since(r.openedAt) > r.MaxAge {
+		return true
+	}
+	return false
+}
+
+func (r *RotatingFile) openExisting() error {
+	if fi, err := os.Stat(r.Filename); err == nil {
+		f, err := os.OpenFile(r.Filename, os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		r.file = f
+		r.size = fi.Size()
+		r.openedAt = fi.ModTime()
+		return nil
+	}
+	return r.openNew()
+}
+
+func (r *RotatingFile) openNew() error {
+	f, err := os.OpenFile(r.Filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	r.file = f
+	r.size = 0
+	r.openedAt = time.Now()
+	return nil
+}
+
+// renameFile is a var so tests can simulate rename failures (e.g. a
+// cross-device link) that are awkward to trigger portably through the
+// real filesystem.
+var renameFile = os.Rename
+
+func (r *RotatingFile) rotate() error {
+	if r.file != nil {
+		r.file.Close()
+		// Set to nil up front: whether or not the rename below succeeds,
+		// this handle is closed and must not be written to again. Leaving
+		// it non-nil on a rename error would make every future Write use
+		// a closed file forever, with no path back to openExisting.
+		r.file = nil
+	}
+	backup := r.Filename + "." + time.Now().Format("20060102T150405.000000000")
+	if err := renameFile(r.Filename, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return r.openNew()
+}
+
+// Close closes the underlying file, if one is open.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func countMatches(t *testing.T, pattern string) int {
+	t.Helper()
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	return len(matches)
+}
+
+func TestRotatingFileSizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "out.log")
+	rf := NewRotatingFile(name, 10, 0)
+	defer rf.Close()
+
+	for i := 0; i < 5; i++ {
+		if _, err := rf.Write([]byte("1234567890")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	if got := countMatches(t, name+".*"); got == 0 {
+		t.Errorf("backups = %d, want at least one rotation past MaxSize", got)
+	}
+	if _, err := os.Stat(name); err != nil {
+		t.Errorf("current log file missing: %v", err)
+	}
+}
+
+func TestRotatingFileAgeRotation(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "out.log")
+	rf := NewRotatingFile(name, 0, time.Millisecond)
+	defer rf.Close()
+
+	if _, err := rf.Write([]byte("first")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := rf.Write([]byte("second")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if got := countMatches(t, name+".*"); got == 0 {
+		t.Errorf("backups = %d, want at least one rotation past MaxAge", got)
+	}
+}
+
+func TestRotatingFileRecoversFromRenameFailure(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "out.log")
+	rf := NewRotatingFile(name, 1, 0)
+	defer rf.Close()
+
+	if _, err := rf.Write([]byte("x")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	orig := renameFile
+	renameFile = func(oldpath, newpath string) error { return errors.New("simulated rename failure") }
+	_, err := rf.Write([]byte("yy"))
+	renameFile = orig
+	if err == nil {
+		t.Fatal("Write during simulated rename failure: want error, got nil")
+	}
+
+	// The failed rotate must not leave the writer stuck on a closed file
+	// handle: a subsequent Write should reopen and succeed.
+	if _, err := rf.Write([]byte("z")); err != nil {
+		t.Fatalf("Write after rename failure did not recover: %v", err)
+	}
+}
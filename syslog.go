@@ -0,0 +1,14 @@
+//go:build !windows
+
+package log
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// NewSyslogWriter returns an io.Writer Sink can use to send entries to the
+// local syslog daemon at the given priority and tag.
+func NewSyslogWriter(priority syslog.Priority, tag string) (io.Writer, error) {
+	return syslog.New(priority, tag)
+}
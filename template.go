@@ -0,0 +1,186 @@
+package log
+
+import "fmt"
+
+// templateInstr is one step of a compiled HeaderTemplate: either a literal
+// run of text, or a named field to substitute from an Entry.
+type templateInstr struct {
+	literal string
+	field   string // "" for a literal-only instruction
+}
+
+var templateFields = map[string]bool{
+	"time": true, "level": true, "file": true, "line": true, "func": true, "msg": true,
+}
+
+// parseTemplate compiles a template string such as
+// "{time} [{level}] {file}:{line} {func} {msg}" into an instruction list
+// once, so that rendering an Entry later is allocation-free beyond the
+// output buffer itself.
+func parseTemplate(tmpl string) ([]templateInstr, error) {
+	var instrs []templateInstr
+	i := 0
+	for i < len(tmpl) {
+		start := i
+		for start < len(tmpl) && tmpl[start] != '{' {
+			start++
+		}
+		if start > i {
+			instrs = append(instrs, templateInstr{literal: tmpl[i:start]})
+		}
+		if start == len(tmpl) {
+			break
+		}
+		end := start + 1
+		for end < len(tmpl) && tmpl[end] != '}' {
+			end++
+		}
+		if end == len(tmpl) {
+			return nil, fmt.Errorf("log: unterminated field in template %q", tmpl)
+		}
+		field := tmpl[start+1 : end]
+		if !templateFields[field] {
+			return nil, fmt.Errorf("log: unknown template field %q", field)
+		}
+		instrs = append(instrs, templateInstr{field: field})
+		i = end + 1
+	}
+	return instrs, nil
+}
+
+func mustParseTemplate(tmpl string) []templateInstr {
+	instrs, err := parseTemplate(tmpl)
+	if err != nil {
+		panic(err)
+	}
+	return instrs
+}
+
+// defaultHeaderTemplate is the single-line default header layout:
+// timestamp, bracketed level, file:line, calling function, then the message.
+const defaultHeaderTemplate = "{time} [{level}] {file}:{line} {func} {msg}"
+
+var defaultTemplate = mustParseTemplate(defaultHeaderTemplate)
+
+// renderTemplate writes e through instrs into buf. flags gates the same
+// fields the stdlib log package's flags always gated: {time} on
+// Ldate/Ltime/Lmicroseconds/LUTC, and {file}/{line}/{func} on
+// Lshortfile/Llongfile, so a Logger built with a partial flag set (as
+// accepted by SetFlags) suppresses the fields it always used to. prefix is
+// only consulted at the {msg} field, and only when Lmsgprefix is set.
+func renderTemplate(buf []byte, instrs []templateInstr, e Entry, flags int, prefix string) []byte {
+	for _, ins := range instrs {
+		if ins.field == "" {
+			buf = append(buf, ins.literal...)
+			continue
+		}
+		switch ins.field {
+		case "time":
+			if flags&(Ldate|Ltime|Lmicroseconds) != 0 {
+				t := e.Time
+				if flags&LUTC != 0 {
+					t = t.UTC()
+				}
+				if flags&Ldate != 0 {
+					year, month, day := t.Date()
+					itoa(&buf, year, 4)
+					buf = append(buf, '/')
+					itoa(&buf, int(month), 2)
+					buf = append(buf, '/')
+					itoa(&buf, day, 2)
+					if flags&(Ltime|Lmicroseconds) != 0 {
+						buf = append(buf, ' ')
+					}
+				}
+				if flags&(Ltime|Lmicroseconds) != 0 {
+					hour, min, sec := t.Clock()
+					itoa(&buf, hour, 2)
+					buf = append(buf, ':')
+					itoa(&buf, min, 2)
+					buf = append(buf, ':')
+					itoa(&buf, sec, 2)
+					if flags&Lmicroseconds != 0 {
+						buf = append(buf, '.')
+						itoa(&buf, t.Nanosecond()/1e3, 6)
+					}
+				}
+			}
+		case "level":
+			buf = append(buf, e.Level.String()...)
+		case "file":
+			if flags&(Lshortfile|Llongfile) != 0 {
+				file := e.File
+				if flags&Lshortfile != 0 {
+					short := file
+					for i := len(file) - 1; i > 0; i-- {
+						if file[i] == '/' {
+							short = file[i+1:]
+							break
+						}
+					}
+					file = short
+				}
+				buf = append(buf, file...)
+			}
+		case "line":
+			if flags&(Lshortfile|Llongfile) != 0 {
+				itoa(&buf, e.Line, -1)
+			}
+		case "func":
+			if flags&(Lshortfile|Llongfile) != 0 {
+				buf = append(buf, e.Func...)
+			}
+		case "msg":
+			if flags&Lmsgprefix != 0 {
+				buf = append(buf, prefix...)
+				if len(prefix) > 0 && prefix[len(prefix)-1] != ' ' {
+					buf = append(buf, ' ')
+				}
+			}
+			buf = append(buf, e.Message...)
+			if e.Err != nil {
+				buf = append(buf, ": "...)
+				buf = append(buf, e.Err.Error()...)
+			}
+		}
+	}
+	return buf
+}
+
+// SetTemplate parses tmpl and installs it as the header layout for the
+// logger's TextFormatter. If the installed Formatter is not a
+// *TextFormatter (e.g. JSON or logfmt), it is replaced by a new
+// TextFormatter for the logger's output. It returns an error if tmpl
+// references an unknown field.
+// Named fields: {time} {level} {file} {line} {func} {msg}.
+func (l *Logger) SetTemplate(tmpl string) error {
+	instrs, err := parseTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+	tf, ok := l.Formatter().(*TextFormatter)
+	if !ok {
+		tf = NewTextFormatter(l.currentOut(), l.Flags(), l.Prefix())
+	}
+	next := *tf
+	next.Template = instrs
+	l.SetFormatter(&next)
+	l.template.Store(&tmpl)
+	return nil
+}
+
+// Template returns the raw template string last passed to SetTemplate, or
+// defaultHeaderTemplate if SetTemplate has not been called.
+func (l *Logger) Template() string {
+	if p := l.template.Load(); p != nil {
+		return *p
+	}
+	return defaultHeaderTemplate
+}
+
+// SetTemplate parses tmpl and installs it as the standard logger's header
+// layout. See (*Logger).SetTemplate.
+func SetTemplate(tmpl string) error { return std.SetTemplate(tmpl) }
+
+// Template returns the standard logger's current header template.
+func Template() string { return std.Template() }